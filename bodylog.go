@@ -0,0 +1,162 @@
+package ginzap
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BodyLogging captures request and response bodies alongside the access log. It is
+// disabled by default; enable it via WithBodyLogging or Config.BodyLogging.
+type BodyLogging struct {
+	// MaxRequestBody is the maximum number of request body bytes captured.
+	// 0 disables request body capture.
+	MaxRequestBody int
+	// MaxResponseBody is the maximum number of response body bytes captured.
+	// 0 disables response body capture.
+	MaxResponseBody int
+	// ContentTypes allowlists which Content-Type values are captured, e.g.
+	// "application/json" or "text/*". A nil/empty list allows every content type.
+	ContentTypes []string
+}
+
+var bodyBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// limitedBuffer caps how many bytes are retained while still tracking the true number
+// of bytes written, so callers can tell whether the capture was truncated.
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	max   int
+	total int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	l.total += len(p)
+	if remaining := l.max - l.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			p = p[:remaining]
+		}
+		l.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+func (l *limitedBuffer) truncated() bool {
+	return l.total > l.buf.Len()
+}
+
+// bodyLogWriter mirrors every write into a capped buffer while still writing through to
+// the real gin.ResponseWriter.
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	captured *limitedBuffer
+}
+
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	w.captured.Write(b) // nolint: errcheck // limitedBuffer.Write never errors
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	w.captured.Write([]byte(s)) // nolint: errcheck
+	return w.ResponseWriter.WriteString(s)
+}
+
+func contentTypeAllowed(contentType string, allowlist []string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	ct, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		ct = contentType
+	}
+	for _, allowed := range allowlist {
+		if strings.HasSuffix(allowed, "/*") {
+			if strings.HasPrefix(ct, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+			continue
+		}
+		if allowed == ct {
+			return true
+		}
+	}
+	return false
+}
+
+// captureBody wraps c.Request.Body and c.Writer, when bl is non-nil, so the bytes
+// flowing through them can be logged afterward. When redactor is non-nil, JSON string
+// leaves in the captured bodies are redacted before logging. It returns a func that
+// builds the log fields for whatever was captured and releases the pooled buffers; the
+// func is safe to call via both a normal call after c.Next() and a defer guarding
+// against panics, since only the first call does any work.
+func captureBody(c *gin.Context, bl *BodyLogging, redactor Redactor) func() []zap.Field {
+	if bl == nil {
+		return func() []zap.Field { return nil }
+	}
+
+	var reqBuf, respBuf *bytes.Buffer
+	var reqCap, respCap *limitedBuffer
+
+	if bl.MaxRequestBody > 0 && c.Request.Body != nil && contentTypeAllowed(c.GetHeader("Content-Type"), bl.ContentTypes) {
+		reqBuf = bodyBufferPool.Get().(*bytes.Buffer)
+		reqBuf.Reset()
+		reqCap = &limitedBuffer{buf: reqBuf, max: bl.MaxRequestBody}
+		c.Request.Body = io.NopCloser(io.TeeReader(c.Request.Body, reqCap))
+	}
+
+	if bl.MaxResponseBody > 0 {
+		respBuf = bodyBufferPool.Get().(*bytes.Buffer)
+		respBuf.Reset()
+		respCap = &limitedBuffer{buf: respBuf, max: bl.MaxResponseBody}
+		c.Writer = &bodyLogWriter{ResponseWriter: c.Writer, captured: respCap}
+	}
+
+	released := false
+	return func() []zap.Field {
+		if released {
+			return nil
+		}
+		released = true
+
+		var fields []zap.Field
+		if reqCap != nil {
+			// Copy out of the pooled buffer, and read everything else off reqCap,
+			// before returning it: the zap.ByteString field below is only serialized
+			// later by the caller, by which point another request may have reused
+			// and overwritten reqBuf.
+			body := append([]byte(nil), reqBuf.Bytes()...)
+			truncated := reqCap.truncated()
+			bodyBufferPool.Put(reqBuf)
+			if redactor != nil {
+				body = redactJSONBody(body, redactor)
+			}
+			fields = append(fields,
+				zap.ByteString("request_body", body),
+				zap.Bool("request_body_truncated", truncated),
+			)
+		}
+		if respCap != nil {
+			body := append([]byte(nil), respBuf.Bytes()...)
+			truncated := respCap.truncated()
+			bodyBufferPool.Put(respBuf)
+			if contentTypeAllowed(c.Writer.Header().Get("Content-Type"), bl.ContentTypes) {
+				if redactor != nil {
+					body = redactJSONBody(body, redactor)
+				}
+				fields = append(fields,
+					zap.ByteString("response_body", body),
+					zap.Bool("response_body_truncated", truncated),
+				)
+			}
+		}
+		return fields
+	}
+}
@@ -0,0 +1,178 @@
+package ginzap
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoggerWithBodyLogging(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		BodyLogging: &BodyLogging{
+			MaxRequestBody:  4,
+			MaxResponseBody: 100,
+			ContentTypes:    []string{"application/json"},
+		},
+	}))
+
+	r.POST(testPath, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		_ = body
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "POST", testPath, strings.NewReader(`{"hello":"world"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(res, req)
+
+	if len(observed.All()) != 1 {
+		t.Fatalf("Log should be 1 line but there're %d", len(observed.All()))
+	}
+
+	logLine := observed.All()[0]
+	reqBodyField, ok := fieldByKey(logLine.Context, "request_body")
+	if !ok || string(reqBodyField.Interface.([]byte)) != `{"he` {
+		t.Fatalf("request_body field missing or wrong: %+v", reqBodyField)
+	}
+	if f, ok := fieldByKey(logLine.Context, "request_body_truncated"); !ok || f.Integer != 1 {
+		t.Fatalf("request_body_truncated should be true: %+v", f)
+	}
+
+	respBodyField, ok := fieldByKey(logLine.Context, "response_body")
+	if !ok || string(respBodyField.Interface.([]byte)) != `{"ok":true}` {
+		t.Fatalf("response_body field missing or wrong: %+v", respBodyField)
+	}
+	if f, ok := fieldByKey(logLine.Context, "response_body_truncated"); !ok || f.Integer != 0 {
+		t.Fatalf("response_body_truncated should be false: %+v", f)
+	}
+}
+
+func TestLoggerWithBodyLoggingAndRedactionTogether(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		BodyLogging: &BodyLogging{MaxRequestBody: 1024, ContentTypes: []string{"application/json"}},
+		Redactor: func(key, value string) string {
+			if key == "password" {
+				return "REDACTED"
+			}
+			return value
+		},
+	}))
+
+	r.POST(testPath, func(c *gin.Context) {
+		_, _ = io.ReadAll(c.Request.Body)
+		c.Status(204)
+	})
+
+	res := httptest.NewRecorder()
+	// 123456789012345678 is beyond float64's 2^53 exact-integer range, so a body
+	// redacted through a map[string]interface{} round trip would silently change its
+	// digits once redaction (enabled here via Redactor) touched the same body.
+	req, _ := http.NewRequestWithContext(ctx, "POST", testPath, strings.NewReader(`{"id":123456789012345678,"password":"hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	reqBodyField, ok := fieldByKey(logLine.Context, "request_body")
+	if !ok {
+		t.Fatalf("request_body field missing")
+	}
+	body := string(reqBodyField.Interface.([]byte))
+	if !strings.Contains(body, `"id":123456789012345678`) {
+		t.Fatalf("large integer should be preserved exactly, got %q", body)
+	}
+	if strings.Contains(body, "hunter2") {
+		t.Fatalf("password should be redacted, got %q", body)
+	}
+}
+
+func TestLoggerWithBodyLoggingConcurrentRequestsDontCorruptEachOther(t *testing.T) {
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		BodyLogging: &BodyLogging{MaxRequestBody: 100, MaxResponseBody: 100},
+	}))
+
+	r.POST(testPath, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(200, string(body))
+	})
+
+	const n = 50
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			payload := strings.Repeat(strconv.Itoa(i%10), 10)
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testPath, strings.NewReader(payload))
+			r.ServeHTTP(res, req)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(observed.All()) != n {
+		t.Fatalf("expected %d logged lines, got %d", n, len(observed.All()))
+	}
+	for _, logLine := range observed.All() {
+		reqBodyField, ok := fieldByKey(logLine.Context, "request_body")
+		if !ok {
+			t.Fatalf("request_body field missing")
+		}
+		respBodyField, ok := fieldByKey(logLine.Context, "response_body")
+		if !ok {
+			t.Fatalf("response_body field missing")
+		}
+		if string(reqBodyField.Interface.([]byte)) != string(respBodyField.Interface.([]byte)) {
+			t.Fatalf("request_body %q should match the echoed response_body %q; a pooled buffer was likely reused before logging", reqBodyField.Interface, respBodyField.Interface)
+		}
+	}
+}
+
+func TestLoggerWithBodyLoggingContentTypeDisallowed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		BodyLogging: &BodyLogging{
+			MaxRequestBody: 100,
+			ContentTypes:   []string{"application/json"},
+		},
+	}))
+
+	r.POST(testPath, func(c *gin.Context) {
+		_, _ = io.ReadAll(c.Request.Body)
+		c.String(200, "ok")
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "POST", testPath, strings.NewReader("plain text"))
+	req.Header.Set("Content-Type", "text/plain")
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	if _, ok := fieldByKey(logLine.Context, "request_body"); ok {
+		t.Fatalf("request_body should not be captured for a disallowed content type")
+	}
+}
@@ -0,0 +1,64 @@
+package ginzap
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrorLogMode controls how a request's c.Errors are logged.
+type ErrorLogMode int
+
+const (
+	// ErrorLogModePerError logs each error on its own line. This is the default.
+	ErrorLogModePerError ErrorLogMode = iota
+	// ErrorLogModeCombined collapses every error into a single line's message.
+	ErrorLogModeCombined
+	// ErrorLogModeArray logs one line carrying the full set of access-log fields plus
+	// a structured "errors" array, one object per *gin.Error.
+	ErrorLogModeArray
+)
+
+// errorArray implements zapcore.ArrayMarshaler over a gin.Context's errors, emitting
+// one object per error with type, err and meta fields.
+type errorArray []*gin.Error
+
+func (a errorArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range a {
+		if err := enc.AppendObject(errorObject{e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type errorObject struct {
+	err *gin.Error
+}
+
+func (o errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("type", errorTypeString(o.err.Type))
+	enc.AddString("err", o.err.Err.Error())
+	if o.err.Meta != nil {
+		return enc.AddReflected("meta", o.err.Meta)
+	}
+	return nil
+}
+
+func errorTypeString(t gin.ErrorType) string {
+	switch t {
+	case gin.ErrorTypeBind:
+		return "bind"
+	case gin.ErrorTypeRender:
+		return "render"
+	case gin.ErrorTypePrivate:
+		return "private"
+	case gin.ErrorTypePublic:
+		return "public"
+	case gin.ErrorTypeAny:
+		return "any"
+	default:
+		return fmt.Sprintf("0x%x", uint64(t))
+	}
+}
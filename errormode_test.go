@@ -0,0 +1,67 @@
+package ginzap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggerErrorLogModeArray(t *testing.T) {
+	errorPath := "/error"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		ErrorLogMode: ErrorLogModeArray,
+	}))
+
+	r.GET(errorPath, func(c *gin.Context) {
+		c.Error(errors.New("error1")).SetType(gin.ErrorTypePrivate).SetMeta("meta1")
+		c.Error(errors.New("error2"))
+		c.JSON(500, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", errorPath, nil)
+	r.ServeHTTP(res, req)
+
+	if len(observed.All()) != 1 {
+		t.Fatalf("Log should be 1 line but there're %d", len(observed.All()))
+	}
+
+	logLine := observed.All()[0]
+	if logLine.Message != errorPath {
+		t.Fatalf("message should be the request path but was %q", logLine.Message)
+	}
+	if _, ok := fieldByKey(logLine.Context, "status"); !ok {
+		t.Fatalf("access-log fields should still be present alongside the errors array")
+	}
+
+	errorsField, ok := fieldByKey(logLine.Context, "errors")
+	if !ok {
+		t.Fatalf("errors field missing")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	if err := enc.AddArray("errors", errorsField.Interface.(zapcore.ArrayMarshaler)); err != nil {
+		t.Fatal(err)
+	}
+	errs, ok := enc.Fields["errors"].([]interface{})
+	if !ok || len(errs) != 2 {
+		t.Fatalf("errors array should have 2 entries, got %+v", enc.Fields["errors"])
+	}
+	first, ok := errs[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("first element isn't an object, got %T", errs[0])
+	}
+	if first["type"] != "private" || first["err"] != "error1" || first["meta"] != "meta1" {
+		t.Fatalf("unexpected first error object: %+v", first)
+	}
+}
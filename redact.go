@@ -0,0 +1,258 @@
+package ginzap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redactor rewrites a single captured value before it is logged: a header value, a
+// query parameter value, or (when body logging is enabled) a JSON string leaf. key is
+// the header name, query parameter name, or JSON object key the value belongs to.
+type Redactor func(key, value string) string
+
+var defaultRedactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Proxy-Authorization": true,
+}
+
+// DefaultRedactor replaces the value of Authorization, Cookie, Set-Cookie and
+// Proxy-Authorization headers with "REDACTED" and passes everything else through
+// unchanged. It is used whenever RequestHeaders/ResponseHeaders are configured without
+// an explicit Config.Redactor.
+func DefaultRedactor(key, value string) string {
+	if defaultRedactedHeaders[http.CanonicalHeaderKey(key)] {
+		return "REDACTED"
+	}
+	return value
+}
+
+func (conf *Config) redactionEnabled() bool {
+	return conf.Redactor != nil || len(conf.RequestHeaders) > 0 || len(conf.ResponseHeaders) > 0
+}
+
+func (conf *Config) redactor() Redactor {
+	if conf.Redactor != nil {
+		return conf.Redactor
+	}
+	return DefaultRedactor
+}
+
+// headerObject marshals a fixed list of header names from header, redacting each value,
+// as an object field (e.g. http.request.headers: {...}).
+type headerObject struct {
+	header   http.Header
+	names    []string
+	redactor Redactor
+}
+
+func (h headerObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, name := range h.names {
+		values := h.header.Values(name)
+		switch len(values) {
+		case 0:
+			continue
+		case 1:
+			enc.AddString(name, h.redactor(name, values[0]))
+		default:
+			// a multi-valued header (e.g. Set-Cookie) would silently lose every value past
+			// the first under Get/AddString, so log the full set as an array instead.
+			if err := enc.AddArray(name, redactedHeaderValues{values: values, name: name, redactor: h.redactor}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func headersField(key string, header http.Header, names []string, redactor Redactor) zap.Field {
+	return zap.Object(key, headerObject{header: header, names: names, redactor: redactor})
+}
+
+// redactedHeaderValues marshals a multi-valued header's values as an array, redacting each
+// one individually.
+type redactedHeaderValues struct {
+	values   []string
+	name     string
+	redactor Redactor
+}
+
+func (r redactedHeaderValues) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range r.values {
+		enc.AppendString(r.redactor(r.name, v))
+	}
+	return nil
+}
+
+// redactedQuery re-encodes rawQuery with every parameter value passed through redactor.
+func redactedQuery(rawQuery string, redactor Redactor) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redactQueryManually(rawQuery, redactor)
+	}
+	for key, vals := range values {
+		for i, v := range vals {
+			vals[i] = redactor(key, v)
+		}
+	}
+	return values.Encode()
+}
+
+// redactQueryManually redacts a raw query string that url.ParseQuery refused to parse -
+// notably the legacy ";"-separated format, which Go rejects outright since 1.17. It
+// fails closed: a segment it can't confidently split into key=value is redacted in
+// full rather than passed through unredacted.
+func redactQueryManually(rawQuery string, redactor Redactor) string {
+	sep := byte('&')
+	if !strings.ContainsRune(rawQuery, '&') && strings.ContainsRune(rawQuery, ';') {
+		sep = ';'
+	}
+	parts := strings.Split(rawQuery, string(sep))
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			parts[i] = "REDACTED"
+			continue
+		}
+		key, value := part[:eq], part[eq+1:]
+		decodedKey, err := url.QueryUnescape(key)
+		if err != nil {
+			parts[i] = "REDACTED"
+			continue
+		}
+		decodedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			parts[i] = key + "=REDACTED"
+			continue
+		}
+		parts[i] = key + "=" + url.QueryEscape(redactor(decodedKey, decodedValue))
+	}
+	return strings.Join(parts, string(sep))
+}
+
+// redactJSONBody redacts every string leaf of a JSON document through redactor, keyed by
+// its enclosing object field name. body is returned unchanged if it isn't valid JSON.
+//
+// It walks the raw token stream rather than round-tripping through json.Unmarshal/
+// json.Marshal via map[string]interface{}: that round trip decodes every JSON number as a
+// float64, silently losing precision on integers beyond 2^53 (e.g. large IDs, snowflake
+// timestamps), and re-marshaling a map loses the original object key order. Walking tokens
+// directly preserves both.
+func redactJSONBody(body []byte, redactor Redactor) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := redactJSONValue(dec, &buf, "", redactor); err != nil {
+		return body
+	}
+	// A valid capture is exactly one JSON value; trailing garbage means this wasn't a
+	// clean JSON document (or was truncated by the body size cap) and is left alone.
+	if dec.More() {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// redactJSONValue reads one JSON value from dec and writes its redacted form to buf. key
+// is the enclosing object field name the value was read for (used when the value itself
+// is a string leaf); it's ignored for array elements and top-level values.
+func redactJSONValue(dec *json.Decoder, buf *bytes.Buffer, key string, redactor Redactor) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return redactJSONObject(dec, buf, redactor)
+		case '[':
+			return redactJSONArray(dec, buf, redactor)
+		default:
+			return fmt.Errorf("ginzap: unexpected JSON delimiter %q", t)
+		}
+	case string:
+		return writeJSONString(buf, redactor(key, t))
+	case json.Number:
+		buf.WriteString(t.String())
+		return nil
+	case bool:
+		if t {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+		return nil
+	case nil:
+		buf.WriteString("null")
+		return nil
+	default:
+		return fmt.Errorf("ginzap: unexpected JSON token %T", tok)
+	}
+}
+
+func redactJSONObject(dec *json.Decoder, buf *bytes.Buffer, redactor Redactor) error {
+	buf.WriteByte('{')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return fmt.Errorf("ginzap: unexpected JSON object key token %T", keyTok)
+		}
+		if err := writeJSONString(buf, key); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := redactJSONValue(dec, buf, key, redactor); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	_, err := dec.Token() // consume the closing '}'
+	return err
+}
+
+func redactJSONArray(dec *json.Decoder, buf *bytes.Buffer, redactor Redactor) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := redactJSONValue(dec, buf, "", redactor); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	_, err := dec.Token() // consume the closing ']'
+	return err
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
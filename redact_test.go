@@ -0,0 +1,187 @@
+package ginzap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggerWithHeadersRedaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		RequestHeaders: []string{"Authorization", "X-Request-Id"},
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath+"?token=secret&page=2", nil)
+	req.Header.Set("Authorization", "Bearer abc123")
+	req.Header.Set("X-Request-Id", "req-1")
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	headersField, ok := fieldByKey(logLine.Context, "http.request.headers")
+	if !ok {
+		t.Fatalf("http.request.headers field missing")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	if err := headersField.Interface.(zapcore.ObjectMarshaler).MarshalLogObject(enc); err != nil {
+		t.Fatal(err)
+	}
+	if enc.Fields["Authorization"] != "REDACTED" {
+		t.Fatalf("Authorization should be redacted, got %v", enc.Fields["Authorization"])
+	}
+	if enc.Fields["X-Request-Id"] != "req-1" {
+		t.Fatalf("X-Request-Id should pass through, got %v", enc.Fields["X-Request-Id"])
+	}
+
+	// the default redactor only targets well-known credential headers, so an unrelated
+	// query parameter like "token" passes through untouched.
+	queryField, ok := fieldByKey(logLine.Context, "query")
+	if !ok || !strings.Contains(queryField.String, "secret") {
+		t.Fatalf("query value should be unchanged by the default redactor: %s", queryField.String)
+	}
+}
+
+func TestLoggerWithCustomRedactor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		Redactor: func(key, value string) string {
+			if key == "token" {
+				return "***"
+			}
+			return value
+		},
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath+"?token=secret&page=2", nil)
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	queryField, ok := fieldByKey(logLine.Context, "query")
+	if !ok || queryField.String != "page=2&token=%2A%2A%2A" {
+		t.Fatalf("query should have token redacted, got %q", queryField.String)
+	}
+}
+
+func TestRedactedQuerySemicolonSeparatedFailsClosed(t *testing.T) {
+	redactor := func(key, value string) string {
+		if key == "token" {
+			return "REDACTED"
+		}
+		return value
+	}
+
+	// url.ParseQuery rejects the legacy ";"-separated format outright (Go 1.17+), so
+	// this must fall back to the manual splitter rather than logging rawQuery verbatim.
+	got := redactedQuery("token=secret;page=2", redactor)
+
+	if strings.Contains(got, "secret") {
+		t.Fatalf("token value should be redacted, got %q", got)
+	}
+	if !strings.Contains(got, "page=2") {
+		t.Fatalf("unrelated page param should pass through, got %q", got)
+	}
+}
+
+func TestRedactedQueryMalformedSegmentFailsClosed(t *testing.T) {
+	redactor := func(key, value string) string { return value }
+
+	// a segment with no "=" can't be split into key/value, so it must be redacted in
+	// full rather than passed through as-is, even though the redactor itself doesn't
+	// redact anything.
+	got := redactedQuery("page=2;bogus", redactor)
+
+	if strings.Contains(got, "bogus") {
+		t.Fatalf("malformed segment should be redacted rather than passed through, got %q", got)
+	}
+	if !strings.Contains(got, "page=2") {
+		t.Fatalf("well-formed segment should pass through, got %q", got)
+	}
+}
+
+func TestRedactJSONBodyPreservesLargeIntegersAndKeyOrder(t *testing.T) {
+	redactor := func(key, value string) string {
+		if key == "name" {
+			return "REDACTED"
+		}
+		return value
+	}
+
+	// 123456789012345678 is beyond float64's 2^53 exact-integer range, so round-tripping
+	// through json.Unmarshal/json.Marshal via interface{} silently changes its digits.
+	in := `{"id":123456789012345678,"name":"x","amount":100}`
+	got := string(redactJSONBody([]byte(in), redactor))
+
+	if !strings.Contains(got, `"id":123456789012345678`) {
+		t.Fatalf("large integer should be preserved exactly, got %q", got)
+	}
+	if !strings.Contains(got, `"name":"REDACTED"`) {
+		t.Fatalf("name should be redacted, got %q", got)
+	}
+	if !strings.Contains(got, `"amount":100`) {
+		t.Fatalf("amount should pass through unchanged, got %q", got)
+	}
+	if strings.Index(got, `"id"`) > strings.Index(got, `"name"`) || strings.Index(got, `"name"`) > strings.Index(got, `"amount"`) {
+		t.Fatalf("object key order should be preserved, got %q", got)
+	}
+}
+
+func TestLoggerWithMultiValuedHeaderRedaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		ResponseHeaders: []string{"Set-Cookie"},
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.Writer.Header().Add("Set-Cookie", "session=abc")
+		c.Writer.Header().Add("Set-Cookie", "csrf=def")
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	headersField, ok := fieldByKey(logLine.Context, "http.response.headers")
+	if !ok {
+		t.Fatalf("http.response.headers field missing")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	if err := headersField.Interface.(zapcore.ObjectMarshaler).MarshalLogObject(enc); err != nil {
+		t.Fatal(err)
+	}
+	values, ok := enc.Fields["Set-Cookie"].([]interface{})
+	if !ok || len(values) != 2 {
+		t.Fatalf("both Set-Cookie values should be logged, got %+v", enc.Fields["Set-Cookie"])
+	}
+	if values[0] != "REDACTED" || values[1] != "REDACTED" {
+		t.Fatalf("both Set-Cookie values should be redacted, got %+v", values)
+	}
+}
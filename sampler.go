@@ -0,0 +1,153 @@
+package ginzap
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Sampler throttles access-log volume for busy endpoints. Requests are bucketed by
+// (method, matched route template, status class); within each SampleTick window the
+// first SampleFirst requests in a bucket are logged as-is, and every SampleThereafter-th
+// request after that is logged with a sampled=true field and a sample_count field
+// giving how many requests (including itself) it represents since the last logged one.
+// The rest of the window's requests in that bucket are dropped.
+//
+// This lets a hot bucket like "GET /healthz 2xx" remain observable without drowning the
+// log pipeline, while leaving every other bucket unaffected (an endpoint that starts
+// erroring gets its own "<method> <route> 5xx" bucket and counter, so a throttled 2xx
+// bucket never hides its errors). It complements SkipPaths, SkipPathRegexps and
+// Skipper, which drop a bucket entirely rather than throttling it.
+//
+// A zero-value Sampler drops every request in every bucket after SampleFirst (zero by
+// default): set SampleThereafter > 0 to keep sampled logging flowing.
+type Sampler struct {
+	// SampleFirst is how many requests per bucket are logged as-is before sampling
+	// kicks in, each SampleTick window.
+	SampleFirst int
+	// SampleThereafter samples every Nth request once SampleFirst is exceeded. A
+	// value <= 0 drops every request in the bucket after the first SampleFirst.
+	SampleThereafter int
+	// SampleTick is the window after which a bucket's counter resets. Defaults to
+	// time.Second if zero.
+	SampleTick time.Duration
+
+	once   sync.Once
+	shards [samplerShardCount]samplerShard
+}
+
+// samplerShardCount bounds lock contention across buckets; a request only ever
+// contends with other requests hashing to the same shard.
+const samplerShardCount = 16
+
+type samplerShard struct {
+	mu      sync.Mutex
+	buckets map[string]*sampleBucket
+}
+
+// evictStale removes every bucket in the shard whose window has already elapsed as of
+// now. Called whenever a bucket is (re)created so the map never retains more than one
+// stale entry per key between accesses, bounding it to the set of keys actually seen
+// within the last tick.
+func (sh *samplerShard) evictStale(now time.Time) {
+	for k, b := range sh.buckets {
+		if !now.Before(b.resetAt) {
+			delete(sh.buckets, k)
+		}
+	}
+}
+
+type sampleBucket struct {
+	resetAt time.Time
+	count   int
+}
+
+// allow reports whether the request in bucket key should be logged at time now, and if
+// it's a sampled (rather than as-is) entry, how many requests it represents.
+func (s *Sampler) allow(key string, now time.Time) (logged bool, sampleCount int) {
+	s.once.Do(func() {
+		for i := range s.shards {
+			s.shards[i].buckets = make(map[string]*sampleBucket)
+		}
+	})
+
+	tick := s.SampleTick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	shard := &s.shards[fnv32a(key)%samplerShardCount]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	b := shard.buckets[key]
+	if b == nil || !now.Before(b.resetAt) {
+		shard.evictStale(now)
+		b = &sampleBucket{resetAt: now.Add(tick)}
+		shard.buckets[key] = b
+	}
+	b.count++
+
+	if b.count <= s.SampleFirst {
+		return true, 0
+	}
+	if s.SampleThereafter <= 0 {
+		return false, 0
+	}
+	if offset := b.count - s.SampleFirst; offset%s.SampleThereafter != 0 {
+		return false, 0
+	}
+	return true, s.SampleThereafter
+}
+
+// unmatchedRouteKey is the bucket route used for requests that didn't match a
+// registered route (c.FullPath() == ""), e.g. 404s. Every such request shares this one
+// key regardless of the raw path requested, so a flood of distinct bogus paths - the
+// exact traffic this sampler exists to tame - throttles down to a single bucket instead
+// of creating one never-reclaimed bucket per distinct path.
+const unmatchedRouteKey = "<unmatched>"
+
+// sampleKey builds the (method, route, status class) bucket key for a request. The
+// matched route template is used rather than the raw path so that e.g. "/users/42" and
+// "/users/7" share a bucket.
+func sampleKey(c *gin.Context) string {
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRouteKey
+	}
+	return c.Request.Method + " " + route + " " + statusClass(c.Writer.Status())
+}
+
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// fnv32a hashes s into a shard index, adapted from zapcore's sampler to avoid a
+// []byte(string) allocation.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
+
+// sampleFields returns the sampled/sample_count fields for a sampled-down entry, or nil
+// for an as-is one.
+func sampleFields(sampleCount int) []zap.Field {
+	if sampleCount == 0 {
+		return nil
+	}
+	return []zap.Field{
+		zap.Bool("sampled", true),
+		zap.Int("sample_count", sampleCount),
+	}
+}
@@ -0,0 +1,122 @@
+package ginzap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoggerSamplerThrottlesBucket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		Sampler: &Sampler{SampleFirst: 2, SampleThereafter: 3, SampleTick: time.Minute},
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	for i := 0; i < 8; i++ {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+		r.ServeHTTP(res, req)
+	}
+
+	// requests 1-2 logged as-is; 3-4 dropped; 5 sampled (3rd since #2); 6-7 dropped; 8 sampled.
+	if len(observed.All()) != 4 {
+		t.Fatalf("expected 4 logged lines, got %d", len(observed.All()))
+	}
+
+	if _, ok := fieldByKey(observed.All()[0].Context, "sampled"); ok {
+		t.Fatalf("first request should be logged as-is, without a sampled field")
+	}
+
+	sampledLine := observed.All()[2]
+	if _, ok := fieldByKey(sampledLine.Context, "sampled"); !ok {
+		t.Fatalf("sampled line should carry a sampled field")
+	}
+	if f, ok := fieldByKey(sampledLine.Context, "sample_count"); !ok || f.Integer != 3 {
+		t.Fatalf("sampled line should carry sample_count=3, got %+v (ok=%v)", f, ok)
+	}
+}
+
+func TestLoggerSamplerResetsAfterTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		Sampler: &Sampler{SampleFirst: 1, SampleThereafter: 0, SampleTick: time.Nanosecond},
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+		r.ServeHTTP(res, req)
+	}
+
+	if len(observed.All()) != 3 {
+		t.Fatalf("each request should be the first in a new tick and log as-is, got %d lines", len(observed.All()))
+	}
+}
+
+func TestSamplerEvictsStaleBuckets(t *testing.T) {
+	s := &Sampler{SampleFirst: 1, SampleTick: time.Nanosecond}
+
+	// 100 distinct keys, each seen once and expired by the time the next is created:
+	// with eviction every shard keeps at most its most recent key.
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		s.allow("stale-key-"+strconv.Itoa(i), now)
+		now = now.Add(time.Millisecond)
+	}
+
+	n := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		n += len(shard.buckets)
+		shard.mu.Unlock()
+	}
+	if n > samplerShardCount {
+		t.Fatalf("expired buckets should be evicted as new ones are created, shards still hold %d", n)
+	}
+}
+
+func TestSampleKeyLumpsUnmatchedRoutes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		Sampler: &Sampler{SampleFirst: 1, SampleThereafter: 0, SampleTick: time.Minute},
+	}))
+
+	for _, path := range []string{"/bogus-a", "/bogus-b", "/bogus-c"} {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, "GET", path, nil)
+		r.ServeHTTP(res, req)
+	}
+
+	// all three hit distinct, never-registered paths but should share the single
+	// "<unmatched>" bucket, so only the first is logged.
+	if len(observed.All()) != 1 {
+		t.Fatalf("unmatched routes should share one sampling bucket, got %d logged lines", len(observed.All()))
+	}
+}
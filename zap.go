@@ -7,12 +7,15 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"regexp"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Ginzap returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap.
@@ -27,6 +30,11 @@ func Ginzap(logger *zap.Logger, timeFormat string, utc bool) gin.HandlerFunc {
 	return Logger(logger, WithTimeFormat(timeFormat), WithUTC(utc))
 }
 
+// OnLevel is like Ginzap but logs successful requests at lvl instead of zap.InfoLevel.
+func OnLevel(logger *zap.Logger, lvl zapcore.Level, timeFormat string, utc bool) gin.HandlerFunc {
+	return GinzapWithConfig(logger, &Config{TimeFormat: timeFormat, UTC: utc, DefaultLevel: lvl})
+}
+
 // RecoveryWithZap returns a gin.HandlerFunc (middleware)
 // that recovers from any panics and logs requests using uber-go/zap.
 // All errors are logged using zap.Error().
@@ -42,29 +50,212 @@ type Option func(c *Config)
 // WithTimeFormat optional a time package format string (e.g. time.RFC3339).(default time.RFC3339Nano).
 func WithTimeFormat(layout string) Option {
 	return func(c *Config) {
-		c.timeFormat = layout
+		c.TimeFormat = layout
 	}
 }
 
 // WithUTC a boolean stating whether to use UTC time zone or local.(default local).
 func WithUTC(b bool) Option {
 	return func(c *Config) {
-		c.utc = b
+		c.UTC = b
 	}
 }
 
 // WithCustomFields optional custom field
 func WithCustomFields(fields ...func(c *gin.Context) zap.Field) Option {
 	return func(c *Config) {
-		c.customFields = fields
+		c.CustomFields = fields
+	}
+}
+
+// WithBodyLogging enables request/response body capture. See BodyLogging.
+func WithBodyLogging(b *BodyLogging) Option {
+	return func(c *Config) {
+		c.BodyLogging = b
+	}
+}
+
+// WithLevelFunc overrides how the log level for successful requests is chosen,
+// taking priority over DefaultLevel when set. See LevelFunc and DefaultLevelFunc.
+func WithLevelFunc(fn LevelFunc) Option {
+	return func(c *Config) {
+		c.LevelFunc = fn
+	}
+}
+
+// WithHeaders captures the given request/response header names as http.request.headers
+// / http.response.headers object fields, redacted through the Redactor.
+func WithHeaders(requestHeaders, responseHeaders []string) Option {
+	return func(c *Config) {
+		c.RequestHeaders = requestHeaders
+		c.ResponseHeaders = responseHeaders
+	}
+}
+
+// WithRedactor overrides the default header/query/body redaction. See Redactor.
+func WithRedactor(r Redactor) Option {
+	return func(c *Config) {
+		c.Redactor = r
+	}
+}
+
+// WithErrorLogMode sets how c.Errors is logged. See ErrorLogMode.
+func WithErrorLogMode(mode ErrorLogMode) Option {
+	return func(c *Config) {
+		c.ErrorLogMode = mode
+	}
+}
+
+// WithSampler throttles access-log volume for busy endpoints. See Sampler.
+func WithSampler(s *Sampler) Option {
+	return func(c *Config) {
+		c.Sampler = s
+	}
+}
+
+// WithTraceContext appends trace_id, span_id and trace_flags fields taken from the
+// trace.SpanContext on the request's context to every access/recovery log line.
+// It is a no-op for requests that don't carry a valid span context, so applications
+// without tracing configured pay nothing for it.
+func WithTraceContext(b bool) Option {
+	return func(c *Config) {
+		c.TraceContext = b
 	}
 }
 
 // Config logger/recover config
 type Config struct {
-	timeFormat   string
-	utc          bool
-	customFields []func(c *gin.Context) zap.Field
+	TimeFormat string
+	UTC        bool
+	// SkipPaths disables logging for these exact request paths.
+	SkipPaths []string
+	// SkipPathRegexps disables logging for any path matching one of these patterns,
+	// in addition to SkipPaths.
+	SkipPathRegexps []*regexp.Regexp
+	// Skipper disables logging for a request when it returns true.
+	Skipper func(c *gin.Context) bool
+	// Sampler, when set, throttles rather than drops logging for busy
+	// (method, route, status class) buckets. See Sampler.
+	Sampler *Sampler
+	// DefaultLevel is the level successful requests are logged at.(default zap.InfoLevel).
+	// Ignored once LevelFunc is set.
+	DefaultLevel zapcore.Level
+	// LevelFunc, when set, computes the level each successful request is logged at,
+	// overriding DefaultLevel. See DefaultLevelFunc for a status/latency-based mapping.
+	LevelFunc LevelFunc
+	// LogErrorsOnce collapses c.Errors into a single log line instead of logging each
+	// error separately. Equivalent to ErrorLogMode: ErrorLogModeCombined; ignored when
+	// ErrorLogMode is set to anything other than ErrorLogModePerError.
+	LogErrorsOnce bool
+	// ErrorLogMode controls how c.Errors is logged (default ErrorLogModePerError).
+	ErrorLogMode ErrorLogMode
+	// TraceContext enables trace_id/span_id/trace_flags enrichment. See WithTraceContext.
+	TraceContext bool
+	// BodyLogging enables request/response body capture. Nil disables it.
+	BodyLogging *BodyLogging
+	// RequestHeaders lists request header names captured as an http.request.headers
+	// object field.
+	RequestHeaders []string
+	// ResponseHeaders lists response header names captured as an http.response.headers
+	// object field.
+	ResponseHeaders []string
+	// Redactor rewrites captured header values, query parameter values and (with
+	// BodyLogging enabled) JSON body string leaves before they're logged. Defaults to
+	// DefaultRedactor once RequestHeaders, ResponseHeaders or Redactor itself is set.
+	Redactor Redactor
+	// CustomFields are appended to every access/recovery log line.
+	CustomFields []func(c *gin.Context) zap.Field
+}
+
+// LevelFunc computes the zap level a successful request should be logged at, given the
+// gin context (after the handler has run) and its latency.
+type LevelFunc func(c *gin.Context, latency time.Duration) zapcore.Level
+
+// DefaultLevelFunc returns a LevelFunc that maps 5xx responses to zap.ErrorLevel, 4xx to
+// zap.WarnLevel and everything else to zap.InfoLevel, additionally promoting any request
+// slower than slowThreshold to zap.WarnLevel. A zero slowThreshold disables that promotion.
+func DefaultLevelFunc(slowThreshold time.Duration) LevelFunc {
+	return func(c *gin.Context, latency time.Duration) zapcore.Level {
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			return zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			return zapcore.WarnLevel
+		case slowThreshold > 0 && latency > slowThreshold:
+			return zapcore.WarnLevel
+		default:
+			return zapcore.InfoLevel
+		}
+	}
+}
+
+// traceFields returns trace_id, span_id and trace_flags fields for the span found on
+// c.Request.Context(), or nil if no valid span context is present.
+func traceFields(c *gin.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(c.Request.Context())
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+		zap.String("trace_flags", sc.TraceFlags().String()),
+	}
+}
+
+// extraFields returns the header/trace/body/custom fields shared by every log line,
+// success or error.
+func (conf *Config) extraFields(c *gin.Context, bodyFields []zap.Field) []zap.Field {
+	var fields []zap.Field
+	if len(conf.RequestHeaders) > 0 {
+		fields = append(fields, headersField("http.request.headers", c.Request.Header, conf.RequestHeaders, conf.redactor()))
+	}
+	if len(conf.ResponseHeaders) > 0 {
+		fields = append(fields, headersField("http.response.headers", c.Writer.Header(), conf.ResponseHeaders, conf.redactor()))
+	}
+	if conf.TraceContext {
+		fields = append(fields, traceFields(c)...)
+	}
+	fields = append(fields, bodyFields...)
+	for _, field := range conf.CustomFields {
+		fields = append(fields, field(c))
+	}
+	return fields
+}
+
+// accessFields returns the full set of access-log fields: status, method, path, query,
+// ip, user-agent, latency, time, plus extraFields.
+func (conf *Config) accessFields(c *gin.Context, path, query string, latency time.Duration, end time.Time, bodyFields []zap.Field) []zap.Field {
+	if conf.redactionEnabled() {
+		query = redactedQuery(query, conf.redactor())
+	}
+	fields := make([]zap.Field, 0, 8+len(conf.CustomFields))
+	fields = append(fields,
+		zap.Int("status", c.Writer.Status()),
+		zap.String("method", c.Request.Method),
+		zap.String("path", path),
+		zap.String("query", query),
+		zap.String("ip", c.ClientIP()),
+		zap.String("user-agent", c.Request.UserAgent()),
+		zap.Duration("latency", latency),
+		zap.String("time", end.Format(conf.TimeFormat)),
+	)
+	return append(fields, conf.extraFields(c, bodyFields)...)
+}
+
+func shouldSkip(c *gin.Context, path string, cfg *Config, skipPaths map[string]bool) bool {
+	if skipPaths[path] {
+		return true
+	}
+	if cfg.Skipper != nil && cfg.Skipper(c) {
+		return true
+	}
+	for _, reg := range cfg.SkipPathRegexps {
+		if reg.MatchString(path) {
+			return true
+		}
+	}
+	return false
 }
 
 // Logger returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap.
@@ -78,48 +269,93 @@ type Config struct {
 //   3. Custom fields.(default nil)
 func Logger(logger *zap.Logger, opts ...Option) gin.HandlerFunc {
 	cfg := Config{
-		time.RFC3339Nano,
-		false,
-		nil,
+		TimeFormat: time.RFC3339Nano,
 	}
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	return GinzapWithConfig(logger, &cfg)
+}
+
+// GinzapWithConfig returns a gin.HandlerFunc (middleware) that logs requests using
+// uber-go/zap, configured directly via a Config rather than functional options.
+func GinzapWithConfig(logger *zap.Logger, conf *Config) gin.HandlerFunc {
+	skipPaths := make(map[string]bool, len(conf.SkipPaths))
+	for _, path := range conf.SkipPaths {
+		skipPaths[path] = true
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		// some evil middlewares modify this values
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
+		var bodyRedactor Redactor
+		if conf.redactionEnabled() {
+			bodyRedactor = conf.redactor()
+		}
+		releaseBody := captureBody(c, conf.BodyLogging, bodyRedactor)
+		defer releaseBody()
 		c.Next()
+		bodyFields := releaseBody()
+
+		if shouldSkip(c, path, conf, skipPaths) {
+			return
+		}
 
 		end := time.Now()
 		latency := end.Sub(start)
-		if cfg.utc {
+
+		var sampled []zap.Field
+		if conf.Sampler != nil {
+			logged, sampleCount := conf.Sampler.allow(sampleKey(c), end)
+			if !logged {
+				return
+			}
+			sampled = sampleFields(sampleCount)
+		}
+
+		if conf.UTC {
 			end = end.UTC()
 		}
 
 		if len(c.Errors) > 0 {
-			// Append error field if this is an erroneous request.
-			for _, e := range c.Errors.Errors() {
-				logger.Error(e)
+			// A disabled Error level costs only this check; the field slice and the
+			// per-error message formatting below are skipped entirely.
+			if logger.Check(zapcore.ErrorLevel, path) == nil {
+				return
+			}
+
+			mode := conf.ErrorLogMode
+			if mode == ErrorLogModePerError && conf.LogErrorsOnce {
+				mode = ErrorLogModeCombined
 			}
-		} else {
-			fields := make([]zap.Field, 0, 8+len(cfg.customFields))
-			fields = append(fields,
-				zap.Int("status", c.Writer.Status()),
-				zap.String("method", c.Request.Method),
-				zap.String("path", path),
-				zap.String("query", query),
-				zap.String("ip", c.ClientIP()),
-				zap.String("user-agent", c.Request.UserAgent()),
-				zap.String("time", end.Format(cfg.timeFormat)),
-				zap.Duration("latency", latency),
-			)
-			for _, field := range cfg.customFields {
-				fields = append(fields, field(c))
+
+			switch mode {
+			case ErrorLogModeArray:
+				fields := conf.accessFields(c, path, query, latency, end, bodyFields)
+				fields = append(fields, zap.Array("errors", errorArray(c.Errors)))
+				logger.Error(path, append(fields, sampled...)...)
+			case ErrorLogModeCombined:
+				logger.Error(c.Errors.String(), append(conf.extraFields(c, bodyFields), sampled...)...)
+			default:
+				fields := append(conf.extraFields(c, bodyFields), sampled...)
+				for _, e := range c.Errors.Errors() {
+					logger.Error(e, fields...)
+				}
 			}
-			logger.Info(path, fields...)
+			return
+		}
+
+		level := conf.DefaultLevel
+		if conf.LevelFunc != nil {
+			level = conf.LevelFunc(c, latency)
 		}
+		ce := logger.Check(level, path)
+		if ce == nil {
+			return
+		}
+		ce.Write(append(conf.accessFields(c, path, query, latency, end, bodyFields), sampled...)...)
 	}
 }
 
@@ -130,15 +366,13 @@ func Logger(logger *zap.Logger, opts ...Option) gin.HandlerFunc {
 // The stack info is easy to find where the error occurs but the stack info is too large.
 func Recovery(logger *zap.Logger, stack bool, opts ...Option) gin.HandlerFunc {
 	cfg := Config{
-		time.RFC3339Nano,
-		false,
-		nil,
+		TimeFormat: time.RFC3339Nano,
 	}
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 	if stack {
-		cfg.customFields = append(cfg.customFields, func(c *gin.Context) zap.Field {
+		cfg.CustomFields = append(cfg.CustomFields, func(c *gin.Context) zap.Field {
 			return zap.ByteString("stack", debug.Stack())
 		})
 	}
@@ -159,10 +393,14 @@ func Recovery(logger *zap.Logger, stack bool, opts ...Option) gin.HandlerFunc {
 
 				httpRequest, _ := httputil.DumpRequest(c.Request, false)
 				if brokenPipe {
-					logger.Error(c.Request.URL.Path,
+					fields := []zap.Field{
 						zap.Any("error", err),
 						zap.ByteString("request", httpRequest),
-					)
+					}
+					if cfg.TraceContext {
+						fields = append(fields, traceFields(c)...)
+					}
+					logger.Error(c.Request.URL.Path, fields...)
 					// If the connection is dead, we can't write a status to it.
 					c.Error(err.(error)) // nolint: errcheck
 					c.Abort()
@@ -170,16 +408,19 @@ func Recovery(logger *zap.Logger, stack bool, opts ...Option) gin.HandlerFunc {
 				}
 
 				now := time.Now()
-				if cfg.utc {
+				if cfg.UTC {
 					now = now.UTC()
 				}
-				fields := make([]zap.Field, 0, 3+len(cfg.customFields))
+				fields := make([]zap.Field, 0, 3+len(cfg.CustomFields))
 				fields = append(fields,
-					zap.String("time", now.Format(cfg.timeFormat)),
+					zap.String("time", now.Format(cfg.TimeFormat)),
 					zap.Any("error", err),
 					zap.ByteString("request", httpRequest),
 				)
-				for _, field := range cfg.customFields {
+				if cfg.TraceContext {
+					fields = append(fields, traceFields(c)...)
+				}
+				for _, field := range cfg.CustomFields {
 					fields = append(fields, field(c))
 				}
 				logger.Error("[Recovery from panic]", fields...)
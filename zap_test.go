@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -262,3 +263,157 @@ func TestLoggerLogErrorsOnce(t *testing.T) {
 		t.Fatalf("logged message should be \"Error #01: error1\nError #02: error2\" but %s", logLine.Message)
 	}
 }
+
+func fieldByKey(fields []zapcore.Field, key string) (zapcore.Field, bool) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return zapcore.Field{}, false
+}
+
+func TestLoggerWithTraceContext(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	ctx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), sc))
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{TraceContext: true}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+	r.ServeHTTP(res, req)
+
+	if len(observed.All()) != 1 {
+		t.Fatalf("Log should be 1 line but there're %d", len(observed.All()))
+	}
+
+	logLine := observed.All()[0]
+	if f, ok := fieldByKey(logLine.Context, "trace_id"); !ok || f.String != traceID.String() {
+		t.Fatalf("trace_id field missing or wrong: %+v", f)
+	}
+	if f, ok := fieldByKey(logLine.Context, "span_id"); !ok || f.String != spanID.String() {
+		t.Fatalf("span_id field missing or wrong: %+v", f)
+	}
+	if _, ok := fieldByKey(logLine.Context, "trace_flags"); !ok {
+		t.Fatalf("trace_flags field missing")
+	}
+}
+
+func TestLoggerLevelFunc(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		LevelFunc: DefaultLevelFunc(0),
+	}))
+
+	r.GET("/ok", func(c *gin.Context) { c.Status(200) })
+	r.GET("/client-error", func(c *gin.Context) { c.Status(404) })
+	r.GET("/server-error", func(c *gin.Context) { c.Status(503) })
+
+	for path, want := range map[string]zapcore.Level{
+		"/ok":           zapcore.InfoLevel,
+		"/client-error": zapcore.WarnLevel,
+		"/server-error": zapcore.ErrorLevel,
+	} {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, "GET", path, nil)
+		r.ServeHTTP(res, req)
+
+		logLine := observed.TakeAll()[0]
+		if logLine.Level != want {
+			t.Fatalf("%s: log level should be %s but was %s", path, want, logLine.Level)
+		}
+	}
+}
+
+func TestLoggerLevelFuncSlowRequestPromoted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{
+		LevelFunc: DefaultLevelFunc(time.Millisecond),
+	}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+		c.Status(200)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	if logLine.Level != zapcore.WarnLevel {
+		t.Fatalf("slow request should be promoted to warn but was %s", logLine.Level)
+	}
+}
+
+func TestLoggerDisabledLevelSkipsLogging(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	core, observed := observer.New(zap.WarnLevel)
+	logger := zap.New(core)
+	r.Use(GinzapWithConfig(logger, &Config{DefaultLevel: zapcore.InfoLevel}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+	r.ServeHTTP(res, req)
+
+	if len(observed.All()) != 0 {
+		t.Fatalf("Info level is disabled on the core, nothing should be logged, got %d lines", len(observed.All()))
+	}
+}
+
+func TestLoggerWithTraceContextNoSpan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := gin.New()
+
+	logger, observed := buildDummyLogger()
+	r.Use(GinzapWithConfig(logger, &Config{TraceContext: true}))
+
+	r.GET(testPath, func(c *gin.Context) {
+		c.JSON(204, nil)
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", testPath, nil)
+	r.ServeHTTP(res, req)
+
+	logLine := observed.All()[0]
+	if _, ok := fieldByKey(logLine.Context, "trace_id"); ok {
+		t.Fatalf("trace_id field should not be present without a valid span context")
+	}
+}